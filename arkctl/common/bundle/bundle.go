@@ -0,0 +1,107 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package bundle resolves a --bundle reference of any scheme arkctl deploy
+// understands (file, oci, http(s), s3) to a local file path, downloading and
+// caching it under ~/.arkctl/bundles as needed.
+package bundle
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"serverless.alipay.com/sofa-serverless/arkctl/common/contextutil"
+	"serverless.alipay.com/sofa-serverless/arkctl/common/journal"
+)
+
+// Options carries the registry/endpoint credentials accepted by
+// --bundle-username/--bundle-password/--bundle-insecure. Sources that don't
+// need auth (file) ignore it.
+type Options struct {
+	Username string
+	Password string
+	Insecure bool
+}
+
+// Source knows how to fetch one bundle scheme into the local cache.
+type Source interface {
+	// Fetch resolves rawURL (the full bundle URL, scheme prefix included)
+	// into a local file path, reusing a cached copy when possible.
+	Fetch(ctx *contextutil.Context, rawURL string, opts Options) (string, error)
+}
+
+var sources = map[string]Source{
+	"oci":   ociSource{},
+	"http":  httpSource{},
+	"https": httpSource{},
+	"s3":    s3Source{},
+}
+
+// Resolve returns a local file path for rawURL, fetching and caching it
+// first if rawURL isn't already a file:// path.
+func Resolve(ctx *contextutil.Context, rawURL string, opts Options) (string, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "", fmt.Errorf("parse bundle url %q: %w", rawURL, err)
+	}
+
+	if u.Scheme == "" || u.Scheme == "file" {
+		return strings.TrimPrefix(rawURL, "file://"), nil
+	}
+
+	source, ok := sources[u.Scheme]
+	if !ok {
+		return "", fmt.Errorf("unsupported bundle source %q, expected one of: file, oci, http(s), s3", u.Scheme)
+	}
+
+	// Sources receive the full rawURL (scheme included) rather than a
+	// pre-stripped reference, since some of them (http/https) need the
+	// scheme to know which protocol to actually speak.
+	return source.Fetch(ctx, rawURL, opts)
+}
+
+// cacheDigestDir returns ~/.arkctl/bundles/sha256, creating it if necessary.
+// Sources should create any temp file they need to rename into this
+// directory inside it, so the final rename is always same-filesystem.
+func cacheDigestDir() (string, error) {
+	cacheDir, err := journal.BundleCacheDir()
+	if err != nil {
+		return "", err
+	}
+
+	dir := filepath.Join(cacheDir, "sha256")
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", fmt.Errorf("create bundle cache dir: %w", err)
+	}
+
+	return dir, nil
+}
+
+// cachePath returns the path a bundle identified by digest (a bare sha256
+// hex string, no "sha256:" prefix) should live at under ~/.arkctl/bundles,
+// creating its parent directory if necessary.
+func cachePath(digest string) (string, error) {
+	dir, err := cacheDigestDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(dir, digest), nil
+}
@@ -0,0 +1,99 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package bundle
+
+import (
+	"testing"
+
+	"serverless.alipay.com/sofa-serverless/arkctl/common/contextutil"
+)
+
+// recordingSource is a Source stub that records the rawURL it was asked to
+// fetch and returns a fixed path, so Resolve's scheme dispatch can be
+// verified without touching a real registry, server, or bucket.
+type recordingSource struct {
+	gotRawURL string
+}
+
+func (s *recordingSource) Fetch(ctx *contextutil.Context, rawURL string, opts Options) (string, error) {
+	s.gotRawURL = rawURL
+	return "/cache/resolved", nil
+}
+
+func TestResolveSchemeDispatch(t *testing.T) {
+	cases := []struct {
+		name    string
+		rawURL  string
+		want    string
+		wantErr bool
+	}{
+		{name: "bare path defaults to file", rawURL: "/local/my-biz-1.0.0-ark-biz.jar", want: "/local/my-biz-1.0.0-ark-biz.jar"},
+		{name: "file scheme strips prefix", rawURL: "file:///local/my-biz-1.0.0-ark-biz.jar", want: "/local/my-biz-1.0.0-ark-biz.jar"},
+		{name: "unsupported scheme", rawURL: "ftp://example.com/my-biz.jar", wantErr: true},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := Resolve(nil, tc.rawURL, Options{})
+			if tc.wantErr {
+				if err == nil {
+					t.Fatalf("Resolve(%q) = %q, want error", tc.rawURL, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Resolve(%q) unexpected error: %v", tc.rawURL, err)
+			}
+			if got != tc.want {
+				t.Fatalf("Resolve(%q) = %q, want %q", tc.rawURL, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestResolveDispatchesToRegisteredSource(t *testing.T) {
+	cases := []struct {
+		scheme string
+		rawURL string
+	}{
+		{scheme: "oci", rawURL: "oci://registry.example.com/team/my-biz:1.0.0"},
+		{scheme: "http", rawURL: "http://artifacts.example.com/my-biz-1.0.0-ark-biz.jar"},
+		{scheme: "https", rawURL: "https://artifacts.example.com/my-biz-1.0.0-ark-biz.jar"},
+		{scheme: "s3", rawURL: "s3://my-bucket/my-biz-1.0.0-ark-biz.jar"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.scheme, func(t *testing.T) {
+			stub := &recordingSource{}
+			prev := sources[tc.scheme]
+			sources[tc.scheme] = stub
+			defer func() { sources[tc.scheme] = prev }()
+
+			got, err := Resolve(nil, tc.rawURL, Options{})
+			if err != nil {
+				t.Fatalf("Resolve(%q) unexpected error: %v", tc.rawURL, err)
+			}
+			if got != "/cache/resolved" {
+				t.Fatalf("Resolve(%q) = %q, want the stub source's path", tc.rawURL, got)
+			}
+			if stub.gotRawURL != tc.rawURL {
+				t.Fatalf("Fetch() received rawURL %q, want %q (scheme must not be stripped)", stub.gotRawURL, tc.rawURL)
+			}
+		})
+	}
+}
@@ -0,0 +1,156 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package bundle
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"oras.land/oras-go/v2/content"
+	"oras.land/oras-go/v2/registry/remote"
+	"oras.land/oras-go/v2/registry/remote/auth"
+	"oras.land/oras-go/v2/registry/remote/credentials"
+	"oras.land/oras-go/v2/registry/remote/retry"
+
+	"serverless.alipay.com/sofa-serverless/arkctl/common/contextutil"
+)
+
+// arkBizLayerMediaType is the media type arkctl expects the ark-biz jar to
+// be pushed under when a module is published to an OCI registry.
+const arkBizLayerMediaType = "application/vnd.sofa.arkbiz.jar"
+
+// ociSource fetches a bundle pushed as an OCI artifact, e.g.
+// oci://registry.example.com/team/my-biz:1.0.3.
+type ociSource struct{}
+
+func (ociSource) Fetch(ctx *contextutil.Context, rawURL string, opts Options) (string, error) {
+	logger := contextutil.GetLogger(ctx)
+
+	ref := strings.TrimPrefix(rawURL, "oci://")
+
+	repoPath, _, found := strings.Cut(ref, ":")
+	if !found {
+		repoPath, _, _ = strings.Cut(ref, "@")
+	}
+
+	repo, err := remote.NewRepository(ref)
+	if err != nil {
+		return "", fmt.Errorf("open oci repository %q: %w", ref, err)
+	}
+	repo.PlainHTTP = opts.Insecure
+	repo.Client = &auth.Client{
+		Client:     retry.DefaultClient,
+		Cache:      auth.NewCache(),
+		Credential: ociCredential(repo.Reference.Registry, opts),
+	}
+
+	logger.WithField("ref", ref).Info("resolving oci bundle.")
+
+	goCtx := context.Background()
+	manifestDesc, err := repo.Resolve(goCtx, ref)
+	if err != nil {
+		return "", fmt.Errorf("resolve oci reference %q: %w", ref, err)
+	}
+
+	manifestBytes, err := content.FetchAll(goCtx, repo, manifestDesc)
+	if err != nil {
+		return "", fmt.Errorf("fetch oci manifest for %q: %w", ref, err)
+	}
+
+	var manifest ocispec.Manifest
+	if err := json.Unmarshal(manifestBytes, &manifest); err != nil {
+		return "", fmt.Errorf("parse oci manifest for %q: %w", ref, err)
+	}
+
+	var layer *ocispec.Descriptor
+	for i := range manifest.Layers {
+		if manifest.Layers[i].MediaType == arkBizLayerMediaType {
+			layer = &manifest.Layers[i]
+			break
+		}
+	}
+	if layer == nil {
+		return "", fmt.Errorf("%q has no layer of media type %s", repoPath, arkBizLayerMediaType)
+	}
+
+	digest := layer.Digest.Encoded()
+	cacheDir, err := cacheDigestDir()
+	if err != nil {
+		return "", err
+	}
+	dst := filepath.Join(cacheDir, digest)
+	if _, err := os.Stat(dst); err == nil {
+		logger.WithField("digest", layer.Digest.String()).Info("bundle already cached, skipping pull.")
+		return dst, nil
+	}
+
+	// content.FetchAll verifies the returned bytes against layer.Digest
+	// before returning, so a corrupted or tampered blob surfaces as an error
+	// here rather than being silently cached.
+	blob, err := content.FetchAll(goCtx, repo, *layer)
+	if err != nil {
+		return "", fmt.Errorf("fetch bundle layer %s: %w", layer.Digest, err)
+	}
+
+	// Write through a temp file in the same cache dir and rename into place,
+	// so a kill or full disk mid-write can never leave a truncated file at
+	// dst that a later run's os.Stat check above would treat as a valid hit.
+	tmp, err := os.CreateTemp(cacheDir, "download-*.tmp")
+	if err != nil {
+		return "", fmt.Errorf("create temp file for bundle layer %s: %w", layer.Digest, err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(blob); err != nil {
+		tmp.Close()
+		return "", fmt.Errorf("cache bundle layer %s: %w", layer.Digest, err)
+	}
+	if err := tmp.Close(); err != nil {
+		return "", fmt.Errorf("cache bundle layer %s: %w", layer.Digest, err)
+	}
+	if err := os.Rename(tmp.Name(), dst); err != nil {
+		return "", fmt.Errorf("cache bundle layer %s: %w", layer.Digest, err)
+	}
+
+	return dst, nil
+}
+
+// ociCredential resolves the credential to use for registry, preferring
+// --bundle-username/--bundle-password and otherwise falling back to
+// DOCKER_CONFIG (or ~/.docker/config.json).
+func ociCredential(registry string, opts Options) auth.CredentialFunc {
+	if opts.Username != "" {
+		return auth.StaticCredential(registry, auth.Credential{
+			Username: opts.Username,
+			Password: opts.Password,
+		})
+	}
+
+	return func(ctx context.Context, reg string) (auth.Credential, error) {
+		store, err := credentials.NewStoreFromDocker(credentials.StoreOptions{})
+		if err != nil {
+			return auth.EmptyCredential, nil
+		}
+		return store.Get(ctx, reg)
+	}
+}
@@ -0,0 +1,214 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package journal keeps a local, append-only record of every deploy arkctl
+// has performed, so that `arkctl history` and `arkctl rollback` can inspect
+// and replay past deploys without talking to any remote service.
+package journal
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// Record is one entry in a biz's deployment journal, written after every
+// successful deploy.
+type Record struct {
+	Time            time.Time `json:"time"`
+	BizName         string    `json:"bizName"`
+	BizVersion      string    `json:"bizVersion"`
+	BundleURL       string    `json:"bundleUrl"`
+	BundleSHA256    string    `json:"bundleSha256"`
+	RunType         string    `json:"runType"`
+	Coordinate      string    `json:"coordinate,omitempty"`
+	Port            int       `json:"port"`
+	PreviousVersion string    `json:"previousVersion,omitempty"`
+}
+
+// rootDir returns ~/.arkctl, the root of all arkctl local state.
+func rootDir() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("resolve home directory: %w", err)
+	}
+	return filepath.Join(home, ".arkctl"), nil
+}
+
+// HistoryPath returns the path to bizName's journal file, creating its
+// parent directory if necessary.
+func HistoryPath(bizName string) (string, error) {
+	root, err := rootDir()
+	if err != nil {
+		return "", err
+	}
+
+	dir := filepath.Join(root, "history")
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", fmt.Errorf("create history dir: %w", err)
+	}
+
+	return filepath.Join(dir, bizName+".jsonl"), nil
+}
+
+// BundleCacheDir returns ~/.arkctl/bundles, creating it if necessary.
+func BundleCacheDir() (string, error) {
+	root, err := rootDir()
+	if err != nil {
+		return "", err
+	}
+
+	dir := filepath.Join(root, "bundles")
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", fmt.Errorf("create bundle cache dir: %w", err)
+	}
+
+	return dir, nil
+}
+
+var (
+	bizLocksMu sync.Mutex
+	bizLocks   = map[string]*sync.Mutex{}
+)
+
+// lockFor returns the mutex serializing journal access for bizName,
+// creating it on first use.
+func lockFor(bizName string) *sync.Mutex {
+	bizLocksMu.Lock()
+	defer bizLocksMu.Unlock()
+
+	l, ok := bizLocks[bizName]
+	if !ok {
+		l = &sync.Mutex{}
+		bizLocks[bizName] = l
+	}
+	return l
+}
+
+// AppendWithPrevious atomically reads bizName's current last version and
+// appends the record build returns for it, holding bizName's lock across
+// both steps. Callers that deploy the same biz from multiple goroutines
+// (e.g. a fleet rollout across pods) must go through this instead of
+// ReadAll+Append, or a racing reader can see its own just-appended record
+// and compute PreviousVersion as the version it just deployed.
+func AppendWithPrevious(bizName string, build func(previousVersion string) Record) error {
+	l := lockFor(bizName)
+	l.Lock()
+	defer l.Unlock()
+
+	records, err := ReadAll(bizName)
+	if err != nil {
+		return err
+	}
+
+	previousVersion := ""
+	if len(records) > 0 {
+		previousVersion = records[len(records)-1].BizVersion
+	}
+
+	return Append(bizName, build(previousVersion))
+}
+
+// Append records a new deploy for bizName.
+func Append(bizName string, record Record) error {
+	path, err := HistoryPath(bizName)
+	if err != nil {
+		return err
+	}
+
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return fmt.Errorf("open history file: %w", err)
+	}
+	defer f.Close()
+
+	line, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("marshal history record: %w", err)
+	}
+
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("write history record: %w", err)
+	}
+
+	return nil
+}
+
+// FindPrevious returns the record for the version deployed immediately
+// before records' most recent entry, as recorded by its PreviousVersion
+// field. It's shared by `arkctl rollback --previous` and execHealthCheck's
+// automatic rollback after a failed health check, so a fix to one lookup
+// can't drift out of sync with the other.
+func FindPrevious(records []Record) (*Record, error) {
+	if len(records) == 0 {
+		return nil, fmt.Errorf("no deployment history recorded")
+	}
+
+	last := records[len(records)-1]
+	if last.PreviousVersion == "" {
+		return nil, fmt.Errorf("no version recorded before %s, nothing to roll back to", last.BizVersion)
+	}
+
+	for i := len(records) - 1; i >= 0; i-- {
+		if records[i].BizVersion == last.PreviousVersion {
+			return &records[i], nil
+		}
+	}
+	return nil, fmt.Errorf("previous version %s not found in history", last.PreviousVersion)
+}
+
+// ReadAll returns every record in bizName's journal, oldest first. It
+// returns an empty slice (not an error) if the biz has never been deployed.
+func ReadAll(bizName string) ([]Record, error) {
+	path, err := HistoryPath(bizName)
+	if err != nil {
+		return nil, err
+	}
+
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("open history file: %w", err)
+	}
+	defer f.Close()
+
+	var records []Record
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		if len(line) == 0 {
+			continue
+		}
+
+		var record Record
+		if err := json.Unmarshal(line, &record); err != nil {
+			return nil, fmt.Errorf("parse history record: %w", err)
+		}
+		records = append(records, record)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("read history file: %w", err)
+	}
+
+	return records, nil
+}
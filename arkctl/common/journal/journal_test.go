@@ -0,0 +1,137 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package journal
+
+import (
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestFindPrevious(t *testing.T) {
+	history := []Record{
+		{BizVersion: "1.0.0"},
+		{BizVersion: "1.0.1", PreviousVersion: "1.0.0"},
+		{BizVersion: "1.0.2", PreviousVersion: "1.0.1"},
+	}
+
+	cases := []struct {
+		name        string
+		records     []Record
+		wantVersion string
+		wantErr     bool
+	}{
+		{name: "no history", records: nil, wantErr: true},
+		{name: "finds the version before the most recent deploy", records: history, wantVersion: "1.0.1"},
+		{name: "no previous version recorded on the most recent deploy", records: []Record{{BizVersion: "1.0.0"}}, wantErr: true},
+		{
+			name: "previous version recorded but missing from history",
+			records: []Record{
+				{BizVersion: "1.0.2", PreviousVersion: "0.9.0"},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			previous, err := FindPrevious(tc.records)
+			if tc.wantErr {
+				if err == nil {
+					t.Fatalf("FindPrevious() = %v, want error", previous)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("FindPrevious() unexpected error: %v", err)
+			}
+			if previous.BizVersion != tc.wantVersion {
+				t.Fatalf("FindPrevious() version = %q, want %q", previous.BizVersion, tc.wantVersion)
+			}
+		})
+	}
+}
+
+func TestAppendReadAllRoundTrip(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	records, err := ReadAll("never-deployed-biz")
+	if err != nil {
+		t.Fatalf("ReadAll() on unknown biz unexpected error: %v", err)
+	}
+	if len(records) != 0 {
+		t.Fatalf("ReadAll() on unknown biz = %v, want empty", records)
+	}
+
+	want := []Record{
+		{Time: time.Unix(1, 0).UTC(), BizName: "my-biz", BizVersion: "1.0.0"},
+		{Time: time.Unix(2, 0).UTC(), BizName: "my-biz", BizVersion: "1.0.1", PreviousVersion: "1.0.0"},
+	}
+	for _, record := range want {
+		if err := Append(record.BizName, record); err != nil {
+			t.Fatalf("Append(%+v) unexpected error: %v", record, err)
+		}
+	}
+
+	got, err := ReadAll("my-biz")
+	if err != nil {
+		t.Fatalf("ReadAll() unexpected error: %v", err)
+	}
+	if len(got) != len(want) {
+		t.Fatalf("ReadAll() = %d records, want %d", len(got), len(want))
+	}
+	for i := range want {
+		if !got[i].Time.Equal(want[i].Time) || got[i].BizVersion != want[i].BizVersion || got[i].PreviousVersion != want[i].PreviousVersion {
+			t.Fatalf("ReadAll()[%d] = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestAppendWithPreviousSerializesConcurrentWriters(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	const writers = 20
+	var wg sync.WaitGroup
+	wg.Add(writers)
+	for i := 0; i < writers; i++ {
+		go func(i int) {
+			defer wg.Done()
+			_ = AppendWithPrevious("concurrent-biz", func(previousVersion string) Record {
+				return Record{BizName: "concurrent-biz", BizVersion: previousVersion + "x"}
+			})
+		}(i)
+	}
+	wg.Wait()
+
+	records, err := ReadAll("concurrent-biz")
+	if err != nil {
+		t.Fatalf("ReadAll() unexpected error: %v", err)
+	}
+	if len(records) != writers {
+		t.Fatalf("ReadAll() = %d records, want %d (a lost write means a race)", len(records), writers)
+	}
+	// Each writer appends the version it read plus "x", so serialized writers
+	// produce "x", "xx", "xxx", ...; a duplicate means two writers read the
+	// same previous version.
+	for i, record := range records {
+		if want := strings.Repeat("x", i+1); record.BizVersion != want {
+			t.Fatalf("ReadAll()[%d].BizVersion = %q, want %q (writers were not serialized)", i, record.BizVersion, want)
+		}
+	}
+}
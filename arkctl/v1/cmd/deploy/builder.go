@@ -0,0 +1,148 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package deploy
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"serverless.alipay.com/sofa-serverless/arkctl/common/cmdutil"
+	"serverless.alipay.com/sofa-serverless/arkctl/common/contextutil"
+)
+
+// builderFlag lets users force a specific build backend instead of relying on
+// auto-detection from the files present in buildFlag.
+var builderFlag string
+
+const (
+	builderNameMaven  = "maven"
+	builderNameGradle = "gradle"
+	builderNameBazel  = "bazel"
+)
+
+// Builder abstracts the toolchain used to turn a project directory into an
+// ark-biz jar. Each implementation knows how to invoke its own build tool and
+// where the resulting artifact will show up.
+type Builder interface {
+	// Name returns the identifier accepted by --builder.
+	Name() string
+	// Build runs the underlying toolchain against buildFlag and reports
+	// success, logging through runBuildCommand.
+	Build(ctx *contextutil.Context) bool
+	// ArtifactGlob returns the filename glob used to locate the produced
+	// ark-biz artifact under the project directory.
+	ArtifactGlob() string
+}
+
+type mavenBuilder struct{}
+
+func (mavenBuilder) Name() string { return builderNameMaven }
+
+func (mavenBuilder) ArtifactGlob() string { return "*-ark-biz.jar" }
+
+func (mavenBuilder) Build(ctx *contextutil.Context) bool {
+	return runBuildCommand(ctx, buildFlag, "mvn", "clean", "package", "-Dmaven.test.skip=true")
+}
+
+type gradleBuilder struct{}
+
+func (gradleBuilder) Name() string { return builderNameGradle }
+
+func (gradleBuilder) ArtifactGlob() string { return filepath.Join("build", "libs", "*-ark-biz.jar") }
+
+func (gradleBuilder) Build(ctx *contextutil.Context) bool {
+	gradlew := filepath.Join(buildFlag, "gradlew")
+	if _, err := os.Stat(gradlew); err != nil {
+		return runBuildCommand(ctx, buildFlag, "gradle", "build", "-x", "test")
+	}
+	return runBuildCommand(ctx, buildFlag, gradlew, "build", "-x", "test")
+}
+
+type bazelBuilder struct{}
+
+func (bazelBuilder) Name() string { return builderNameBazel }
+
+func (bazelBuilder) ArtifactGlob() string { return filepath.Join("bazel-bin", "*-ark-biz.jar") }
+
+func (bazelBuilder) Build(ctx *contextutil.Context) bool {
+	return runBuildCommand(ctx, buildFlag, "bazel", "build", "//...")
+}
+
+// runBuildCommand runs a build tool in workDir and streams its output,
+// logging "build bundle failed!" and returning false if the command fails.
+func runBuildCommand(ctx *contextutil.Context, workDir, name string, args ...string) bool {
+	logger := contextutil.GetLogger(ctx)
+
+	cmd := cmdutil.BuildCommandWithWorkDir(ctx, workDir, name, args...)
+
+	logger.WithField("dir", workDir).WithField("builder", name).Info("start to build bundle.")
+	if err := cmd.Exec(); err != nil {
+		logger.WithError(err).Error("build bundle failed!")
+	}
+
+	go func() {
+		for line := range cmd.Output() {
+			fmt.Println(line)
+		}
+	}()
+
+	if err := <-cmd.Wait(); err != nil {
+		logger.WithError(err).Error("build bundle failed!")
+		return false
+	}
+
+	if err := cmd.GetExitError(); err != nil {
+		logger.WithError(err).Error("build bundle failed!")
+		return false
+	}
+
+	return true
+}
+
+// detectBuilder picks a Builder for dir, honoring builderFlag when set and
+// otherwise probing for the marker file of each supported toolchain.
+func detectBuilder(dir string) (Builder, error) {
+	switch builderFlag {
+	case "":
+		// fall through to auto-detection below
+	case builderNameMaven:
+		return mavenBuilder{}, nil
+	case builderNameGradle:
+		return gradleBuilder{}, nil
+	case builderNameBazel:
+		return bazelBuilder{}, nil
+	default:
+		return nil, fmt.Errorf("unknown builder %q, expected one of: maven, gradle, bazel", builderFlag)
+	}
+
+	if _, err := os.Stat(filepath.Join(dir, "pom.xml")); err == nil {
+		return mavenBuilder{}, nil
+	}
+	if _, err := os.Stat(filepath.Join(dir, "build.gradle")); err == nil {
+		return gradleBuilder{}, nil
+	}
+	if _, err := os.Stat(filepath.Join(dir, "build.gradle.kts")); err == nil {
+		return gradleBuilder{}, nil
+	}
+	if _, err := os.Stat(filepath.Join(dir, "BUILD.bazel")); err == nil {
+		return bazelBuilder{}, nil
+	}
+
+	return nil, fmt.Errorf("could not detect a build backend in %s: expected pom.xml, build.gradle[.kts] or BUILD.bazel", dir)
+}
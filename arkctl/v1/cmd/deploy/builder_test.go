@@ -0,0 +1,105 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package deploy
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestDetectBuilder(t *testing.T) {
+	cases := []struct {
+		name        string
+		builderFlag string
+		markers     []string
+		wantName    string
+		wantErr     bool
+	}{
+		{
+			name:        "forced maven",
+			builderFlag: builderNameMaven,
+			markers:     []string{"build.gradle"},
+			wantName:    builderNameMaven,
+		},
+		{
+			name:        "unknown forced builder",
+			builderFlag: "ant",
+			wantErr:     true,
+		},
+		{
+			name:     "auto-detect maven",
+			markers:  []string{"pom.xml"},
+			wantName: builderNameMaven,
+		},
+		{
+			name:     "auto-detect gradle groovy",
+			markers:  []string{"build.gradle"},
+			wantName: builderNameGradle,
+		},
+		{
+			name:     "auto-detect gradle kotlin",
+			markers:  []string{"build.gradle.kts"},
+			wantName: builderNameGradle,
+		},
+		{
+			name:     "auto-detect bazel",
+			markers:  []string{"BUILD.bazel"},
+			wantName: builderNameBazel,
+		},
+		{
+			name:     "maven takes priority over gradle",
+			markers:  []string{"pom.xml", "build.gradle"},
+			wantName: builderNameMaven,
+		},
+		{
+			name:    "no marker files",
+			markers: nil,
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			dir := t.TempDir()
+			for _, marker := range tc.markers {
+				if err := os.WriteFile(filepath.Join(dir, marker), []byte{}, 0o644); err != nil {
+					t.Fatalf("write marker %s: %v", marker, err)
+				}
+			}
+
+			prev := builderFlag
+			builderFlag = tc.builderFlag
+			defer func() { builderFlag = prev }()
+
+			builder, err := detectBuilder(dir)
+			if tc.wantErr {
+				if err == nil {
+					t.Fatalf("detectBuilder() = %v, want error", builder)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("detectBuilder() unexpected error: %v", err)
+			}
+			if builder.Name() != tc.wantName {
+				t.Fatalf("detectBuilder() name = %q, want %q", builder.Name(), tc.wantName)
+			}
+		})
+	}
+}
@@ -0,0 +1,94 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package deploy
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"serverless.alipay.com/sofa-serverless/arkctl/common/bundle"
+	"serverless.alipay.com/sofa-serverless/arkctl/common/contextutil"
+	"serverless.alipay.com/sofa-serverless/arkctl/common/journal"
+)
+
+// fetchBundle resolves record's bundle to a local file path usable by
+// execParseBizModel/ark.ParseBizModel, so that rollback can redeploy it.
+//
+// It first checks the bundle cache under ~/.arkctl/bundles, keyed by the
+// recorded sha256, and only falls back to re-fetching from BundleURL on a
+// cache miss. file:// bundles are copied into that cache; oci://, http(s)://
+// and s3:// bundles are re-fetched through the same bundle.Resolve that
+// arkctl deploy uses.
+func fetchBundle(record journal.Record) (string, error) {
+	cacheDir, err := journal.BundleCacheDir()
+	if err != nil {
+		return "", err
+	}
+
+	cachePath := filepath.Join(cacheDir, fmt.Sprintf("%s-%s.jar", record.BizName, record.BundleSHA256))
+	if _, err := os.Stat(cachePath); err == nil {
+		return cachePath, nil
+	}
+
+	u, err := url.Parse(record.BundleURL)
+	if err != nil {
+		return "", fmt.Errorf("parse recorded bundle url %q: %w", record.BundleURL, err)
+	}
+
+	if u.Scheme != "" && u.Scheme != "file" {
+		localPath, err := bundle.Resolve(contextutil.NewContext(context.Background()), record.BundleURL, bundle.Options{
+			Username: bundleUsernameFlag,
+			Password: bundlePasswordFlag,
+			Insecure: bundleInsecureFlag,
+		})
+		if err != nil {
+			return "", fmt.Errorf("re-fetch bundle from %s: %w", record.BundleURL, err)
+		}
+		return localPath, nil
+	}
+
+	srcPath := strings.TrimPrefix(record.BundleURL, "file://")
+	if err := copyFile(srcPath, cachePath); err != nil {
+		return "", fmt.Errorf("cache bundle from %s: %w", srcPath, err)
+	}
+
+	return cachePath, nil
+}
+
+// copyFile copies src to dst, creating dst if it does not already exist.
+func copyFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+
+	_, err = io.Copy(out, in)
+	return err
+}
@@ -24,6 +24,7 @@ import (
 	"path/filepath"
 	"strings"
 
+	"serverless.alipay.com/sofa-serverless/arkctl/common/bundle"
 	"serverless.alipay.com/sofa-serverless/arkctl/common/cmdutil"
 	"serverless.alipay.com/sofa-serverless/arkctl/common/contextutil"
 	"serverless.alipay.com/sofa-serverless/arkctl/common/fileutil"
@@ -39,6 +40,10 @@ var (
 	portFlag   int
 	podFlag    string
 
+	bundleUsernameFlag string
+	bundlePasswordFlag string
+	bundleInsecureFlag bool
+
 	doLocalBuildBundle = false
 )
 
@@ -46,6 +51,9 @@ const (
 	ctxKeyArkService              = "ark.Service"
 	ctxKeyBizModel                = "ark.BizModel"
 	ctxKeyArkContainerRuntimeInfo = "ark.ContainerRuntimeInfo"
+	ctxKeyBuilder                 = "deploy.Builder"
+	ctxKeyBundleURL               = "deploy.BundleURL"
+	ctxKeyBundleSHA256            = "deploy.BundleSHA256"
 )
 
 var DeployCommand = &cobra.Command{
@@ -73,6 +81,9 @@ Scenario 2: Deploy a local bundleFlag to local running ark container:
 Scenario 3: Deploy a local bundleFlag to remote ark container running in k8s podFlag:
 !!Make sure you already have kubectl and exec permission to the k8s cluster in your working environment!!.
 	arkctl deploy --bundle ${url/to/your/bundle} --pod ${namespace}/${name} --dir ${bundle dir inside port} -port ${your ark container port}
+
+Scenario 4: Deploy a bundle published to an OCI registry:
+	arkctl deploy --bundle oci://registry.example.com/team/my-biz:1.0.3 --pod ${namespace}/${name} --port ${your ark container port}
 `,
 	ValidArgs:         nil,
 	ValidArgsFunction: nil,
@@ -85,45 +96,39 @@ Scenario 3: Deploy a local bundleFlag to remote ark container running in k8s pod
 			buildFlag, _ = os.Getwd()
 		}
 
+		if podFlag != "" && selectorFlag != "" {
+			return fmt.Errorf("--pod and --selector are mutually exclusive")
+		}
+
+		if watchFlag && !doLocalBuildBundle {
+			return fmt.Errorf("--watch requires a local build, it is not compatible with --bundle")
+		}
+		if watchFlag && selectorFlag != "" {
+			return fmt.Errorf("--watch is not compatible with --selector")
+		}
+
 		return nil
 	},
 	Run: executeDeploy,
 }
 
-func execMavenBuild(ctx *contextutil.Context) bool {
+func execBuild(ctx *contextutil.Context) bool {
 	logger := contextutil.GetLogger(ctx)
 	if !doLocalBuildBundle {
 		logger.Info("build bundle skipped!")
+		return true
 	}
 
-	mvn := cmdutil.BuildCommandWithWorkDir(
-		ctx,
-		buildFlag,
-		"mvn",
-		"clean", "package", "-Dmaven.test.skip=true")
-
-	logger.WithField("dir", buildFlag).Info("start to build bundle.")
-	if err := mvn.Exec(); err != nil {
-		logger.WithError(err).Error("build bundle failed!")
-	}
-
-	go func() {
-		for line := range mvn.Output() {
-			fmt.Println(line)
-		}
-	}()
-
-	if err := <-mvn.Wait(); err != nil {
+	builder, err := detectBuilder(buildFlag)
+	if err != nil {
 		logger.WithError(err).Error("build bundle failed!")
 		return false
 	}
 
-	if err := mvn.GetExitError(); err != nil {
-		logger.WithError(err).Error("build bundle failed!")
-		return false
-	}
+	logger.WithField("builder", builder.Name()).Info("detected build backend.")
+	ctx.Put(ctxKeyBuilder, builder)
 
-	return true
+	return builder.Build(ctx)
 }
 
 func execParseBizModel(ctx *contextutil.Context) bool {
@@ -132,14 +137,32 @@ func execParseBizModel(ctx *contextutil.Context) bool {
 	)
 
 	bundlePath := bundleFlag
+	var journalURL string
 	if doLocalBuildBundle {
 		searchdir := buildFlag
 		if searchdir == "" {
 			searchdir, _ = os.Getwd()
 		}
 
-		filepath.Walk(searchdir, func(path string, info os.FileInfo, err error) error {
-			if !info.IsDir() && strings.HasSuffix(info.Name(), "-ark-biz.jar") {
+		builder, _ := ctx.Value(ctxKeyBuilder).(Builder)
+		artifactGlob := "*-ark-biz.jar"
+		if builder != nil {
+			artifactGlob = builder.ArtifactGlob()
+		}
+
+		// Scope the walk to the builder's own output directory (e.g.
+		// build/libs for gradle, bazel-bin for bazel) instead of the whole
+		// project tree, so a stale jar left over in an unrelated directory
+		// can't be picked up instead of the artifact this build just produced.
+		// Maven's glob has no directory part, so for maven the whole project
+		// tree is still searched.
+		walkRoot := filepath.Join(searchdir, filepath.Dir(artifactGlob))
+
+		filepath.Walk(walkRoot, func(path string, info os.FileInfo, err error) error {
+			if info == nil || info.IsDir() {
+				return nil
+			}
+			if matched, _ := filepath.Match(filepath.Base(artifactGlob), info.Name()); matched {
 				bundlePath = path
 			}
 			return nil
@@ -150,6 +173,23 @@ func execParseBizModel(ctx *contextutil.Context) bool {
 			return false
 		}
 		bundlePath = "file://" + bundlePath
+		journalURL = bundlePath
+	} else {
+		localPath, err := bundle.Resolve(ctx, bundlePath, bundle.Options{
+			Username: bundleUsernameFlag,
+			Password: bundlePasswordFlag,
+			Insecure: bundleInsecureFlag,
+		})
+		if err != nil {
+			logger.WithError(err).Error("resolve bundle failed!")
+			return false
+		}
+		// journalURL keeps the original rawURL (oci://, https://, s3://) even
+		// after bundlePath is overwritten with the resolved local cache path,
+		// so `arkctl rollback` can re-fetch from the original source later
+		// instead of a cache entry that may since have been evicted.
+		journalURL = bundlePath
+		bundlePath = "file://" + localPath
 	}
 
 	bizModel, err := ark.ParseBizModel(ctx, fileutil.FileUrl(bundlePath))
@@ -159,6 +199,13 @@ func execParseBizModel(ctx *contextutil.Context) bool {
 	}
 
 	ctx.Put(ctxKeyBizModel, bizModel)
+	ctx.Put(ctxKeyBundleURL, journalURL)
+
+	if sha, err := sha256File(strings.TrimPrefix(bundlePath, "file://")); err == nil {
+		ctx.Put(ctxKeyBundleSHA256, sha)
+	} else {
+		logger.WithError(err).Warn("could not hash bundle for deployment journal.")
+	}
 
 	return true
 }
@@ -213,6 +260,8 @@ func execInstall(ctx *contextutil.Context) bool {
 		return false
 	}
 	logger.Info("install biz success!")
+
+	recordDeploy(ctx, arkContainerRuntimeInfo)
 	return true
 }
 
@@ -240,24 +289,62 @@ func generateContext(cmd *cobra.Command) *contextutil.Context {
 // executeDeploy will execute the deploy command
 // 1. build the biz bundle
 // 2. parse the biz model for further usage
-// 3. uninstall the biz bundle in target ark container to prevent conflict
-// 4. install the biz bundle in target ark container
+// 3. uninstall/install the biz bundle in the target ark container(s):
+//   - a single container when --pod is used
+//   - every pod matching --selector, fanned out per --strategy, when a
+//     selector is given instead
+//
+// When --watch is set (single-container deploys only), steps 1-3 keep
+// re-running as source files under --build change.
 func executeDeploy(cobracmd *cobra.Command, _ []string) {
 	c := generateContext(cobracmd)
 
-	todos := []func(context2 *contextutil.Context) bool{
-		execMavenBuild,
+	prepare := []func(context2 *contextutil.Context) bool{
+		execBuild,
 		execParseBizModel,
-		execUnInstall,
-		execInstall,
 	}
-
-	for _, todo := range todos {
+	for _, todo := range prepare {
 		if !todo(c) {
 			return
 		}
 	}
 
+	if !isFleetDeploy() {
+		if !execUnInstall(c) {
+			return
+		}
+		if !execInstall(c) {
+			return
+		}
+		if !execHealthCheck(c) {
+			os.Exit(1)
+		}
+
+		if watchFlag {
+			runWatch(c)
+		}
+		return
+	}
+
+	logger := contextutil.GetLogger(c)
+
+	targets, err := resolveFleetTargets(c)
+	if err != nil {
+		logger.WithError(err).Error("resolve fleet targets failed!")
+		return
+	}
+
+	logger.WithField("count", len(targets)).WithField("strategy", strategyFlag).Info("starting fleet deploy.")
+
+	results, err := deployFleet(c, targets)
+	if err != nil {
+		logger.WithError(err).Error("fleet deploy aborted!")
+	}
+
+	if err := summarizeFleetResults(c, results); err != nil {
+		logger.WithError(err).Error("fleet deploy finished with failures.")
+		os.Exit(1)
+	}
 }
 
 func init() {
@@ -269,15 +356,58 @@ If not provided, arkctl will try to buildFlag the project in current directory.
 
 	DeployCommand.Flags().StringVar(&bundleFlag, "bundle", "", `
 Provide the pre-built bundleFlag url and then deploy it to running containers.
-If not provided, arkctl will try to find the bundleFlag in current directory.
+Accepts a file://, oci://, http(s):// or s3:// url. If not provided, arkctl
+will try to find the bundleFlag in current directory.
+`)
+
+	DeployCommand.Flags().StringVar(&bundleUsernameFlag, "bundle-username", "", `
+Username used to authenticate to the --bundle source (registry basic auth for oci://,
+basic auth for http(s)://, access key id for s3://). Falls back to DOCKER_CONFIG for oci://.
+`)
+
+	DeployCommand.Flags().StringVar(&bundlePasswordFlag, "bundle-password", "", `
+Password/secret used to authenticate to the --bundle source, paired with --bundle-username.
+`)
+
+	DeployCommand.Flags().BoolVar(&bundleInsecureFlag, "bundle-insecure", false, `
+Allow plain HTTP/skip TLS verification when fetching --bundle. Has no effect on s3:// sources.
 `)
 
 	DeployCommand.Flags().StringVar(&podFlag, "pod", "", `
 If Provided, arkctl will try to deploy the bundleFlag to the given podFlag instead of local running process.
+Mutually exclusive with --selector.
+`)
+
+	DeployCommand.Flags().StringVar(&selectorFlag, "selector", "", `
+A Kubernetes label selector (e.g. "app=foo,env=staging"). If provided, arkctl fans the deploy
+out to every pod matching the selector instead of the single pod given by --pod.
+`)
+
+	DeployCommand.Flags().StringVar(&namespaceFlag, "namespace", "default", `
+The namespace to search for pods matching --selector.
+`)
+
+	DeployCommand.Flags().IntVar(&parallelismFlag, "parallelism", 4, `
+The maximum number of pods to deploy to at once when using --selector.
+`)
+
+	DeployCommand.Flags().StringVar(&strategyFlag, "strategy", "parallel", `
+The rollout strategy to use when deploying to multiple pods via --selector.
+One of: parallel, rolling, canary.
+`)
+
+	DeployCommand.Flags().IntVar(&canaryPercentFlag, "canary-percent", 20, `
+The percentage of matching pods to deploy to first when --strategy=canary, before
+verifying biz health and rolling out to the rest.
 `)
 
 	DeployCommand.Flags().IntVar(&portFlag, "port", 1238, `
 The default portFlag of ark container is 1238.
+`)
+
+	DeployCommand.Flags().StringVar(&builderFlag, "builder", "", `
+Force the build backend used to build the project instead of auto-detecting it from
+pom.xml, build.gradle[.kts] or BUILD.bazel. One of: maven, gradle, bazel.
 `)
 
 }
@@ -0,0 +1,267 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package deploy
+
+import (
+	"fmt"
+	"sync"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/clientcmd"
+
+	"serverless.alipay.com/sofa-serverless/arkctl/common/contextutil"
+	"serverless.alipay.com/sofa-serverless/arkctl/v1/service/ark"
+)
+
+const (
+	strategyParallel = "parallel"
+	strategyRolling  = "rolling"
+	strategyCanary   = "canary"
+)
+
+var (
+	selectorFlag      string
+	namespaceFlag     string
+	parallelismFlag   int
+	strategyFlag      string
+	canaryPercentFlag int
+)
+
+// fleetTarget identifies a single pod to deploy to.
+type fleetTarget struct {
+	Namespace string
+	Name      string
+}
+
+func (t fleetTarget) String() string {
+	return fmt.Sprintf("%s/%s", t.Namespace, t.Name)
+}
+
+// fleetResult records the outcome of deploying to a single fleetTarget.
+type fleetResult struct {
+	Target fleetTarget
+	Err    error
+}
+
+// isFleetDeploy reports whether the deploy was invoked with --selector,
+// fanning out to multiple pods instead of the single --pod target.
+func isFleetDeploy() bool {
+	return selectorFlag != ""
+}
+
+// resolveFleetTargets lists every pod matching selectorFlag in namespaceFlag.
+func resolveFleetTargets(ctx *contextutil.Context) ([]fleetTarget, error) {
+	config, err := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(
+		clientcmd.NewDefaultClientConfigLoadingRules(), &clientcmd.ConfigOverrides{}).ClientConfig()
+	if err != nil {
+		return nil, fmt.Errorf("load kubeconfig: %w", err)
+	}
+
+	clientset, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		return nil, fmt.Errorf("build k8s client: %w", err)
+	}
+
+	pods, err := clientset.CoreV1().Pods(namespaceFlag).List(ctx, metav1.ListOptions{
+		LabelSelector: selectorFlag,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("list pods matching %q in namespace %q: %w", selectorFlag, namespaceFlag, err)
+	}
+
+	targets := make([]fleetTarget, 0, len(pods.Items))
+	for _, pod := range pods.Items {
+		targets = append(targets, fleetTarget{Namespace: pod.Namespace, Name: pod.Name})
+	}
+
+	if len(targets) == 0 {
+		return nil, fmt.Errorf("no pods matched selector %q in namespace %q", selectorFlag, namespaceFlag)
+	}
+
+	return targets, nil
+}
+
+// deployToTarget runs the uninstall+install steps against a single pod,
+// reusing the bizModel already built/parsed on the shared context.
+func deployToTarget(ctx *contextutil.Context, target fleetTarget) error {
+	logger := contextutil.GetLogger(ctx)
+
+	arkContainerRuntimeInfo := &ark.ArkContainerRuntimeInfo{
+		RunType:    ark.ArkContainerRunTypeK8s,
+		Port:       &portFlag,
+		Coordinate: target.String(),
+	}
+
+	targetCtx := contextutil.NewContext(ctx)
+	targetCtx.Put(ctxKeyArkService, ctx.Value(ctxKeyArkService))
+	targetCtx.Put(ctxKeyBizModel, ctx.Value(ctxKeyBizModel))
+	targetCtx.Put(ctxKeyArkContainerRuntimeInfo, arkContainerRuntimeInfo)
+	targetCtx.Put(ctxKeyBundleURL, ctx.Value(ctxKeyBundleURL))
+	targetCtx.Put(ctxKeyBundleSHA256, ctx.Value(ctxKeyBundleSHA256))
+
+	logger.WithField("pod", target.String()).Info("deploying to pod.")
+
+	if !execUnInstall(targetCtx) {
+		return fmt.Errorf("uninstall failed on pod %s", target)
+	}
+	if !execInstall(targetCtx) {
+		return fmt.Errorf("install failed on pod %s", target)
+	}
+	if !execHealthCheck(targetCtx) {
+		return fmt.Errorf("health check failed on pod %s", target)
+	}
+
+	return nil
+}
+
+// deployFleet fans execUnInstall/execInstall out to every target according
+// to strategyFlag, bounding concurrency to parallelismFlag, and returns a
+// per-pod report plus the first error encountered, if any.
+func deployFleet(ctx *contextutil.Context, targets []fleetTarget) ([]fleetResult, error) {
+	switch strategyFlag {
+	case strategyParallel:
+		return deployBatch(ctx, targets, parallelismFlag), nil
+	case strategyRolling:
+		return deployRolling(ctx, targets), nil
+	case strategyCanary:
+		return deployCanary(ctx, targets)
+	default:
+		return nil, fmt.Errorf("unknown strategy %q, expected one of: parallel, rolling, canary", strategyFlag)
+	}
+}
+
+// deployBatch deploys targets concurrently, at most concurrency at a time.
+func deployBatch(ctx *contextutil.Context, targets []fleetTarget, concurrency int) []fleetResult {
+	if concurrency <= 0 {
+		concurrency = 1
+	}
+
+	results := make([]fleetResult, len(targets))
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+
+	for i, target := range targets {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, target fleetTarget) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			results[i] = fleetResult{Target: target, Err: deployToTarget(ctx, target)}
+		}(i, target)
+	}
+
+	wg.Wait()
+	return results
+}
+
+// deployRolling deploys parallelismFlag targets at a time, waiting for each
+// batch to finish, including its per-pod health checks, before moving on to
+// the next. A batch with failed pods does not stop the later batches.
+func deployRolling(ctx *contextutil.Context, targets []fleetTarget) []fleetResult {
+	logger := contextutil.GetLogger(ctx)
+
+	batchSize := parallelismFlag
+	if batchSize <= 0 {
+		batchSize = 1
+	}
+
+	results := make([]fleetResult, 0, len(targets))
+	for start := 0; start < len(targets); start += batchSize {
+		end := start + batchSize
+		if end > len(targets) {
+			end = len(targets)
+		}
+
+		batch := targets[start:end]
+		logger.WithField("batch", fmt.Sprintf("%d-%d/%d", start+1, end, len(targets))).Info("rolling deploy: starting batch.")
+		results = append(results, deployBatch(ctx, batch, len(batch))...)
+	}
+
+	return results
+}
+
+// canaryBatchSize rounds percent of total up to the nearest whole target,
+// then clamps to [1, total] so a canary batch always deploys to at least one
+// target (when total > 0) and never more than all of them.
+func canaryBatchSize(total, percent int) int {
+	count := (total*percent + 99) / 100
+	if count < 1 {
+		count = 1
+	}
+	if count > total {
+		count = total
+	}
+	return count
+}
+
+// deployCanary deploys to canaryPercentFlag of targets first, verifies biz
+// health on those pods, then proceeds with the remaining targets in
+// parallel. It bails out before touching the remainder if the canary batch
+// is unhealthy.
+func deployCanary(ctx *contextutil.Context, targets []fleetTarget) ([]fleetResult, error) {
+	logger := contextutil.GetLogger(ctx)
+
+	canaryCount := canaryBatchSize(len(targets), canaryPercentFlag)
+
+	canaryTargets := targets[:canaryCount]
+	remainingTargets := targets[canaryCount:]
+
+	logger.WithField("canaryCount", canaryCount).WithField("total", len(targets)).Info("canary deploy: deploying canary batch.")
+	results := deployBatch(ctx, canaryTargets, parallelismFlag)
+
+	// deployToTarget already runs execHealthCheck per pod (honoring
+	// --health-check), so result.Err alone tells us whether the canary batch
+	// is healthy enough to continue; a second, unconditional checkBizHealth
+	// call here would ignore --health-check=off and abort a rollout the
+	// operator explicitly asked not to gate on activation status.
+	for _, result := range results {
+		if result.Err != nil {
+			return results, fmt.Errorf("canary batch failed, aborting rollout: %w", result.Err)
+		}
+	}
+
+	logger.Info("canary deploy: canary batch healthy, continuing rollout.")
+	results = append(results, deployBatch(ctx, remainingTargets, parallelismFlag)...)
+
+	return results, nil
+}
+
+// summarizeFleetResults logs a per-pod success/failure report and returns an
+// error aggregating every failure, if any occurred.
+func summarizeFleetResults(ctx *contextutil.Context, results []fleetResult) error {
+	logger := contextutil.GetLogger(ctx)
+
+	var failures []string
+	for _, result := range results {
+		if result.Err != nil {
+			logger.WithField("pod", result.Target.String()).WithError(result.Err).Error("deploy failed.")
+			failures = append(failures, fmt.Sprintf("%s: %v", result.Target, result.Err))
+		} else {
+			logger.WithField("pod", result.Target.String()).Info("deploy succeeded.")
+		}
+	}
+
+	logger.WithField("succeeded", len(results)-len(failures)).WithField("failed", len(failures)).Info("deploy report.")
+
+	if len(failures) > 0 {
+		return fmt.Errorf("%d/%d pods failed to deploy: %v", len(failures), len(results), failures)
+	}
+
+	return nil
+}
@@ -0,0 +1,45 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package deploy
+
+import "testing"
+
+func TestCanaryBatchSize(t *testing.T) {
+	cases := []struct {
+		name    string
+		total   int
+		percent int
+		want    int
+	}{
+		{name: "exact division", total: 10, percent: 20, want: 2},
+		{name: "rounds up partial target", total: 10, percent: 15, want: 2},
+		{name: "clamps up to at least one", total: 10, percent: 1, want: 1},
+		{name: "clamps down to total at 100 percent", total: 10, percent: 100, want: 10},
+		{name: "clamps down when percent overshoots", total: 10, percent: 150, want: 10},
+		{name: "single target", total: 1, percent: 10, want: 1},
+		{name: "no targets", total: 0, percent: 50, want: 0},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := canaryBatchSize(tc.total, tc.percent); got != tc.want {
+				t.Fatalf("canaryBatchSize(%d, %d) = %d, want %d", tc.total, tc.percent, got, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,289 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package deploy
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/client-go/tools/portforward"
+	"k8s.io/client-go/transport/spdy"
+
+	"serverless.alipay.com/sofa-serverless/arkctl/common/contextutil"
+	"serverless.alipay.com/sofa-serverless/arkctl/common/fileutil"
+	"serverless.alipay.com/sofa-serverless/arkctl/common/journal"
+	"serverless.alipay.com/sofa-serverless/arkctl/v1/service/ark"
+)
+
+const (
+	healthCheckOff    = "off"
+	healthCheckStatus = "status"
+	healthCheckHTTP   = "http"
+
+	healthPollInterval = 2 * time.Second
+)
+
+var (
+	healthCheckFlag    string
+	healthTimeoutFlag  time.Duration
+	healthHTTPPathFlag string
+	expectStatusFlag   int
+)
+
+func init() {
+	DeployCommand.Flags().StringVar(&healthCheckFlag, "health-check", healthCheckStatus, `
+How to gate a deploy on biz health before considering it done. One of:
+  off:    don't wait, execInstall succeeding is enough.
+  status: poll the ark container's biz status endpoint until ACTIVATED (default).
+  http:   poll --health-http-path and expect --expect-status.
+`)
+
+	DeployCommand.Flags().DurationVar(&healthTimeoutFlag, "health-timeout", 60*time.Second, `
+How long to wait for the biz to become healthy before giving up and automatically
+rolling back to the previously running version.
+`)
+
+	DeployCommand.Flags().StringVar(&healthHTTPPathFlag, "health-http-path", "/", `
+The path to GET against the ark container's exposed port when --health-check=http.
+`)
+
+	DeployCommand.Flags().IntVar(&expectStatusFlag, "expect-status", http.StatusOK, `
+The HTTP status code that counts as healthy when --health-check=http.
+`)
+}
+
+// execHealthCheck polls the just-installed biz until it is healthy or
+// --health-timeout elapses. On timeout or a probe error it automatically
+// reinstalls the previously running version recorded in the deployment
+// journal, so a bad deploy doesn't leave the target worse off than before.
+func execHealthCheck(ctx *contextutil.Context) bool {
+	logger := contextutil.GetLogger(ctx)
+
+	if healthCheckFlag == healthCheckOff {
+		logger.Info("health check skipped.")
+		return true
+	}
+
+	bizModel := ctx.Value(ctxKeyBizModel).(*ark.BizModel)
+
+	logger.WithField("mode", healthCheckFlag).WithField("timeout", healthTimeoutFlag).
+		Info("waiting for biz to become healthy.")
+
+	healthy, err := pollHealth(ctx)
+	if healthy {
+		logger.Info("biz is healthy.")
+		return true
+	}
+
+	if err != nil {
+		logger.WithError(err).Error("health check failed!")
+	} else {
+		logger.WithField("timeout", healthTimeoutFlag).Error("health check timed out!")
+	}
+
+	if rollbackErr := autoRollback(ctx, bizModel.BizName); rollbackErr != nil {
+		logger.WithError(rollbackErr).Error("automatic rollback after failed health check also failed!")
+	} else {
+		logger.Info("automatically rolled back to the last healthy version.")
+	}
+
+	return false
+}
+
+// pollHealth probes the target every healthPollInterval until it reports
+// healthy or --health-timeout elapses.
+func pollHealth(ctx *contextutil.Context) (bool, error) {
+	var probe func() (bool, error)
+	switch healthCheckFlag {
+	case healthCheckStatus:
+		probe = func() (bool, error) { return probeStatus(ctx) }
+	case healthCheckHTTP:
+		probe = func() (bool, error) { return probeHTTP(ctx) }
+	default:
+		return false, fmt.Errorf("unknown --health-check %q, expected one of: off, status, http", healthCheckFlag)
+	}
+
+	return pollUntilHealthy(healthTimeoutFlag, healthPollInterval, probe)
+}
+
+// pollUntilHealthy calls probe every interval until it reports healthy or
+// timeout elapses since the first call, returning the last error probe saw
+// (if any) once it gives up.
+func pollUntilHealthy(timeout, interval time.Duration, probe func() (bool, error)) (bool, error) {
+	deadline := time.Now().Add(timeout)
+
+	var lastErr error
+	for {
+		healthy, err := probe()
+		if healthy {
+			return true, nil
+		}
+		lastErr = err
+
+		if time.Now().After(deadline) {
+			return false, lastErr
+		}
+		time.Sleep(interval)
+	}
+}
+
+// probeStatus queries the ark container's biz status endpoint and reports
+// whether bizModel has reached the ACTIVATED state.
+func probeStatus(ctx *contextutil.Context) (bool, error) {
+	var (
+		arkService              = ctx.Value(ctxKeyArkService).(ark.Service)
+		bizModel                = ctx.Value(ctxKeyBizModel).(*ark.BizModel)
+		arkContainerRuntimeInfo = ctx.Value(ctxKeyArkContainerRuntimeInfo).(*ark.ArkContainerRuntimeInfo)
+	)
+
+	resp, err := arkService.QueryBiz(ctx, ark.QueryBizRequest{
+		BizModel:        *bizModel,
+		TargetContainer: *arkContainerRuntimeInfo,
+	})
+	if err != nil {
+		return false, fmt.Errorf("query biz status: %w", err)
+	}
+
+	for _, info := range resp.BizInfos {
+		if info.BizName == bizModel.BizName && info.BizVersion == bizModel.BizVersion {
+			return info.BizState == ark.BizStateActivated, nil
+		}
+	}
+
+	return false, nil
+}
+
+// probeHTTP performs a GET against --health-http-path on the target
+// container's exposed port and reports whether it returned --expect-status.
+// For a k8s target this goes through the same port-forward path remote
+// deploys use to reach the container.
+func probeHTTP(ctx *contextutil.Context) (bool, error) {
+	arkContainerRuntimeInfo := ctx.Value(ctxKeyArkContainerRuntimeInfo).(*ark.ArkContainerRuntimeInfo)
+
+	if arkContainerRuntimeInfo.RunType != ark.ArkContainerRunTypeK8s {
+		return httpGetStatus(fmt.Sprintf("http://127.0.0.1:%d%s", *arkContainerRuntimeInfo.Port, healthHTTPPathFlag))
+	}
+
+	namespace, name, found := strings.Cut(arkContainerRuntimeInfo.Coordinate, "/")
+	if !found {
+		return false, fmt.Errorf("invalid pod coordinate %q, expected namespace/name", arkContainerRuntimeInfo.Coordinate)
+	}
+
+	return httpGetStatusThroughPortForward(namespace, name, *arkContainerRuntimeInfo.Port, healthHTTPPathFlag)
+}
+
+func httpGetStatus(url string) (bool, error) {
+	resp, err := http.Get(url)
+	if err != nil {
+		return false, err
+	}
+	defer resp.Body.Close()
+
+	return resp.StatusCode == expectStatusFlag, nil
+}
+
+// httpGetStatusThroughPortForward opens a one-off port-forward to pod's
+// containerPort, issues the GET, and tears the forward back down.
+func httpGetStatusThroughPortForward(namespace, name string, containerPort int, path string) (bool, error) {
+	config, err := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(
+		clientcmd.NewDefaultClientConfigLoadingRules(), &clientcmd.ConfigOverrides{}).ClientConfig()
+	if err != nil {
+		return false, fmt.Errorf("load kubeconfig: %w", err)
+	}
+
+	roundTripper, upgrader, err := spdy.RoundTripperFor(config)
+	if err != nil {
+		return false, fmt.Errorf("build spdy round tripper: %w", err)
+	}
+
+	forwardURL := &url.URL{
+		Scheme: "https",
+		Host:   strings.TrimPrefix(config.Host, "https://"),
+		Path:   fmt.Sprintf("/api/v1/namespaces/%s/pods/%s/portforward", namespace, name),
+	}
+	dialer := spdy.NewDialer(upgrader, &http.Client{Transport: roundTripper}, http.MethodPost, forwardURL)
+
+	stopCh := make(chan struct{})
+	defer close(stopCh)
+	readyCh := make(chan struct{})
+
+	pf, err := portforward.New(dialer, []string{fmt.Sprintf("0:%d", containerPort)}, stopCh, readyCh, io.Discard, io.Discard)
+	if err != nil {
+		return false, fmt.Errorf("create port-forward to pod %s/%s: %w", namespace, name, err)
+	}
+
+	errCh := make(chan error, 1)
+	go func() { errCh <- pf.ForwardPorts() }()
+
+	select {
+	case err := <-errCh:
+		return false, fmt.Errorf("port-forward to pod %s/%s: %w", namespace, name, err)
+	case <-readyCh:
+	}
+
+	forwarded, err := pf.GetPorts()
+	if err != nil || len(forwarded) == 0 {
+		return false, fmt.Errorf("resolve forwarded port to pod %s/%s: %w", namespace, name, err)
+	}
+
+	return httpGetStatus(fmt.Sprintf("http://127.0.0.1:%d%s", forwarded[0].Local, path))
+}
+
+// autoRollback reinstalls the version bizName was running before the
+// currently-installed one, as recorded in the deployment journal.
+func autoRollback(ctx *contextutil.Context, bizName string) error {
+	records, err := journal.ReadAll(bizName)
+	if err != nil {
+		return fmt.Errorf("read deployment history: %w", err)
+	}
+
+	previous, err := journal.FindPrevious(records)
+	if err != nil {
+		return err
+	}
+
+	// uninstall the unhealthy version while ctx still points at it.
+	failedVersion := records[len(records)-1].BizVersion
+	if !execUnInstall(ctx) {
+		return fmt.Errorf("uninstall failed version %s", failedVersion)
+	}
+
+	bundlePath, err := fetchBundle(*previous)
+	if err != nil {
+		return fmt.Errorf("fetch previous bundle: %w", err)
+	}
+
+	bizModel, err := ark.ParseBizModel(ctx, fileutil.FileUrl("file://"+bundlePath))
+	if err != nil {
+		return fmt.Errorf("parse previous biz model: %w", err)
+	}
+	ctx.Put(ctxKeyBizModel, bizModel)
+	ctx.Put(ctxKeyBundleURL, "file://"+bundlePath)
+	ctx.Put(ctxKeyBundleSHA256, previous.BundleSHA256)
+
+	if !execInstall(ctx) {
+		return fmt.Errorf("install previous version %s failed", previous.BizVersion)
+	}
+
+	return nil
+}
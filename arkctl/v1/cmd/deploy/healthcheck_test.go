@@ -0,0 +1,76 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package deploy
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestPollUntilHealthy(t *testing.T) {
+	t.Run("healthy on first probe returns immediately", func(t *testing.T) {
+		calls := 0
+		healthy, err := pollUntilHealthy(time.Second, time.Millisecond, func() (bool, error) {
+			calls++
+			return true, nil
+		})
+		if !healthy || err != nil {
+			t.Fatalf("pollUntilHealthy() = (%v, %v), want (true, nil)", healthy, err)
+		}
+		if calls != 1 {
+			t.Fatalf("probe called %d times, want 1", calls)
+		}
+	})
+
+	t.Run("healthy after a few unhealthy probes", func(t *testing.T) {
+		calls := 0
+		healthy, err := pollUntilHealthy(time.Second, time.Millisecond, func() (bool, error) {
+			calls++
+			return calls >= 3, nil
+		})
+		if !healthy || err != nil {
+			t.Fatalf("pollUntilHealthy() = (%v, %v), want (true, nil)", healthy, err)
+		}
+		if calls != 3 {
+			t.Fatalf("probe called %d times, want 3", calls)
+		}
+	})
+
+	t.Run("times out and surfaces the last probe error", func(t *testing.T) {
+		wantErr := errors.New("biz not activated")
+		healthy, err := pollUntilHealthy(5*time.Millisecond, time.Millisecond, func() (bool, error) {
+			return false, wantErr
+		})
+		if healthy {
+			t.Fatalf("pollUntilHealthy() = (true, _), want (false, _)")
+		}
+		if !errors.Is(err, wantErr) {
+			t.Fatalf("pollUntilHealthy() error = %v, want %v", err, wantErr)
+		}
+	})
+
+	t.Run("times out with no error when probe never errors", func(t *testing.T) {
+		healthy, err := pollUntilHealthy(5*time.Millisecond, time.Millisecond, func() (bool, error) {
+			return false, nil
+		})
+		if healthy || err != nil {
+			t.Fatalf("pollUntilHealthy() = (%v, %v), want (false, nil)", healthy, err)
+		}
+	})
+}
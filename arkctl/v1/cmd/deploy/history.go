@@ -0,0 +1,74 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package deploy
+
+import (
+	"context"
+	"fmt"
+
+	"serverless.alipay.com/sofa-serverless/arkctl/common/contextutil"
+	"serverless.alipay.com/sofa-serverless/arkctl/common/journal"
+	"serverless.alipay.com/sofa-serverless/arkctl/v1/cmd/root"
+
+	"github.com/spf13/cobra"
+)
+
+var HistoryCommand = &cobra.Command{
+	Use: "history <bizName>",
+	Short: `
+"show the local deployment journal for a biz module."
+`,
+	Long: `
+The arkctl history command prints every deploy arkctl has recorded for bizName,
+oldest first, from its local journal under ~/.arkctl/history. Use it to find the
+bizVersion to pass to "arkctl rollback --to".
+`,
+	Example: `
+	arkctl history my-biz
+`,
+	Args: cobra.ExactArgs(1),
+	Run:  executeHistory,
+}
+
+func init() {
+	root.RootCmd.AddCommand(HistoryCommand)
+}
+
+func executeHistory(_ *cobra.Command, args []string) {
+	bizName := args[0]
+
+	c := contextutil.NewContext(context.Background())
+	logger := contextutil.GetLogger(c)
+
+	records, err := journal.ReadAll(bizName)
+	if err != nil {
+		logger.WithError(err).Error("read deployment history failed!")
+		return
+	}
+
+	if len(records) == 0 {
+		logger.WithField("bizName", bizName).Info("no deployment history recorded for this biz.")
+		return
+	}
+
+	for _, record := range records {
+		fmt.Printf("%s\tbizVersion=%s\trunType=%s\tcoordinate=%s\tport=%d\tbundleSha256=%s\n",
+			record.Time.Format("2006-01-02T15:04:05Z07:00"),
+			record.BizVersion, record.RunType, record.Coordinate, record.Port, record.BundleSHA256)
+	}
+}
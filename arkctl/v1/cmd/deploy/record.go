@@ -0,0 +1,81 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package deploy
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"os"
+	"time"
+
+	"serverless.alipay.com/sofa-serverless/arkctl/common/contextutil"
+	"serverless.alipay.com/sofa-serverless/arkctl/common/journal"
+	"serverless.alipay.com/sofa-serverless/arkctl/v1/service/ark"
+)
+
+// sha256File returns the hex-encoded sha256 digest of the file at path.
+func sha256File(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// recordDeploy appends a journal entry for a successful deploy to target,
+// so `arkctl history`/`arkctl rollback` can look it up later.
+func recordDeploy(ctx *contextutil.Context, arkContainerRuntimeInfo *ark.ArkContainerRuntimeInfo) {
+	logger := contextutil.GetLogger(ctx)
+
+	bizModel, ok := ctx.Value(ctxKeyBizModel).(*ark.BizModel)
+	if !ok {
+		return
+	}
+
+	bundleURL, _ := ctx.Value(ctxKeyBundleURL).(string)
+	bundleSHA256, _ := ctx.Value(ctxKeyBundleSHA256).(string)
+
+	// AppendWithPrevious reads the last recorded version and appends under
+	// the same per-biz lock, so concurrent recordDeploy calls for the same
+	// biz (a fleet rollout across pods) can't race and corrupt each other's
+	// PreviousVersion.
+	err := journal.AppendWithPrevious(bizModel.BizName, func(previousVersion string) journal.Record {
+		return journal.Record{
+			Time:            time.Now(),
+			BizName:         bizModel.BizName,
+			BizVersion:      bizModel.BizVersion,
+			BundleURL:       bundleURL,
+			BundleSHA256:    bundleSHA256,
+			RunType:         string(arkContainerRuntimeInfo.RunType),
+			Coordinate:      arkContainerRuntimeInfo.Coordinate,
+			Port:            portFlag,
+			PreviousVersion: previousVersion,
+		}
+	})
+	if err != nil {
+		logger.WithError(err).Warn("failed to record deploy in local history journal.")
+	}
+}
@@ -0,0 +1,175 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package deploy
+
+import (
+	"context"
+	"fmt"
+
+	"serverless.alipay.com/sofa-serverless/arkctl/common/contextutil"
+	"serverless.alipay.com/sofa-serverless/arkctl/common/fileutil"
+	"serverless.alipay.com/sofa-serverless/arkctl/common/journal"
+	"serverless.alipay.com/sofa-serverless/arkctl/v1/cmd/root"
+	"serverless.alipay.com/sofa-serverless/arkctl/v1/service/ark"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	rollbackToFlag       string
+	rollbackPreviousFlag bool
+	dryRunFlag           bool
+)
+
+var RollbackCommand = &cobra.Command{
+	Use: "rollback <bizName>",
+	Short: `
+"roll a biz module back to a previously deployed revision."
+`,
+	Long: `
+The arkctl rollback command redeploys a biz module from its local deployment
+journal, giving the same "undo last deploy" ergonomics that container platform
+installers like Argo/Helm provide.
+
+It reads bizName's journal under ~/.arkctl/history, resolves the target
+revision's bundle (reusing the cached copy under ~/.arkctl/bundles, or
+re-fetching it from the recorded bundle URL), and runs the same
+uninstall+install flow "arkctl deploy" used against the original target.
+`,
+	Example: `
+Roll back to the version deployed immediately before the current one:
+	arkctl rollback my-biz --previous
+
+Roll back to a specific bizVersion recorded in history:
+	arkctl rollback my-biz --to 1.0.3
+
+Preview what a rollback would do without touching the target container:
+	arkctl rollback my-biz --previous --dry-run
+`,
+	Args: cobra.ExactArgs(1),
+	Run:  executeRollback,
+}
+
+func init() {
+	root.RootCmd.AddCommand(RollbackCommand)
+
+	RollbackCommand.Flags().StringVar(&rollbackToFlag, "to", "", `
+The bizVersion to roll back to, as recorded by "arkctl history". Mutually exclusive with --previous.
+`)
+
+	RollbackCommand.Flags().BoolVar(&rollbackPreviousFlag, "previous", false, `
+Roll back to the version deployed immediately before the current one. Mutually exclusive with --to.
+`)
+
+	RollbackCommand.Flags().BoolVar(&dryRunFlag, "dry-run", false, `
+Resolve and print the rollback target without actually uninstalling/installing it.
+`)
+}
+
+// resolveRollbackTarget picks the journal record to roll back to out of
+// bizName's history, honoring rollbackToFlag/rollbackPreviousFlag.
+func resolveRollbackTarget(records []journal.Record) (*journal.Record, error) {
+	if len(records) == 0 {
+		return nil, fmt.Errorf("no deployment history recorded for this biz")
+	}
+
+	if rollbackToFlag != "" && rollbackPreviousFlag {
+		return nil, fmt.Errorf("--to and --previous are mutually exclusive")
+	}
+
+	if rollbackPreviousFlag {
+		return journal.FindPrevious(records)
+	}
+
+	if rollbackToFlag == "" {
+		return nil, fmt.Errorf("one of --to or --previous is required")
+	}
+
+	for i := len(records) - 1; i >= 0; i-- {
+		if records[i].BizVersion == rollbackToFlag {
+			return &records[i], nil
+		}
+	}
+	return nil, fmt.Errorf("revision %s not found in history", rollbackToFlag)
+}
+
+func executeRollback(_ *cobra.Command, args []string) {
+	bizName := args[0]
+
+	c := contextutil.NewContext(context.Background())
+	logger := contextutil.GetLogger(c)
+
+	records, err := journal.ReadAll(bizName)
+	if err != nil {
+		logger.WithError(err).Error("read deployment history failed!")
+		return
+	}
+
+	target, err := resolveRollbackTarget(records)
+	if err != nil {
+		logger.WithError(err).Error("resolve rollback target failed!")
+		return
+	}
+
+	logger.WithField("bizVersion", target.BizVersion).
+		WithField("coordinate", target.Coordinate).
+		Info("resolved rollback target.")
+
+	if dryRunFlag {
+		logger.Info("dry-run: skipping uninstall/install.")
+		return
+	}
+
+	bundlePath, err := fetchBundle(*target)
+	if err != nil {
+		logger.WithError(err).Error("resolve rollback bundle failed!")
+		return
+	}
+
+	bizModel, err := ark.ParseBizModel(c, fileutil.FileUrl("file://"+bundlePath))
+	if err != nil {
+		logger.WithError(err).Error("parse biz model failed!")
+		return
+	}
+	c.Put(ctxKeyBizModel, bizModel)
+	c.Put(ctxKeyBundleURL, "file://"+bundlePath)
+	c.Put(ctxKeyBundleSHA256, target.BundleSHA256)
+
+	arkService := ark.BuildService(c)
+	c.Put(ctxKeyArkService, arkService)
+
+	// reuse the shared portFlag var so that execInstall/execUnInstall logging
+	// and recordDeploy (which both read portFlag directly) see the port the
+	// original deploy used.
+	portFlag = target.Port
+	arkContainerRuntimeInfo := &ark.ArkContainerRuntimeInfo{
+		RunType:    ark.ArkContainerRunType(target.RunType),
+		Coordinate: target.Coordinate,
+		Port:       &portFlag,
+	}
+	c.Put(ctxKeyArkContainerRuntimeInfo, arkContainerRuntimeInfo)
+
+	if !execUnInstall(c) {
+		return
+	}
+	if !execInstall(c) {
+		return
+	}
+
+	logger.WithField("bizVersion", target.BizVersion).Info("rollback success!")
+}
@@ -0,0 +1,106 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package deploy
+
+import (
+	"testing"
+
+	"serverless.alipay.com/sofa-serverless/arkctl/common/journal"
+)
+
+func TestResolveRollbackTarget(t *testing.T) {
+	history := []journal.Record{
+		{BizVersion: "1.0.0"},
+		{BizVersion: "1.0.1", PreviousVersion: "1.0.0"},
+		{BizVersion: "1.0.2", PreviousVersion: "1.0.1"},
+	}
+
+	cases := []struct {
+		name        string
+		records     []journal.Record
+		to          string
+		previous    bool
+		wantVersion string
+		wantErr     bool
+	}{
+		{
+			name:    "no history",
+			records: nil,
+			to:      "1.0.0",
+			wantErr: true,
+		},
+		{
+			name:        "to a known version",
+			records:     history,
+			to:          "1.0.0",
+			wantVersion: "1.0.0",
+		},
+		{
+			name:    "to an unknown version",
+			records: history,
+			to:      "9.9.9",
+			wantErr: true,
+		},
+		{
+			name:        "previous",
+			records:     history,
+			previous:    true,
+			wantVersion: "1.0.1",
+		},
+		{
+			name:     "to and previous are mutually exclusive",
+			records:  history,
+			to:       "1.0.0",
+			previous: true,
+			wantErr:  true,
+		},
+		{
+			name:    "neither to nor previous set",
+			records: history,
+			wantErr: true,
+		},
+		{
+			name:     "previous with no prior version recorded",
+			records:  []journal.Record{{BizVersion: "1.0.0"}},
+			previous: true,
+			wantErr:  true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			prevTo, prevPrevious := rollbackToFlag, rollbackPreviousFlag
+			rollbackToFlag, rollbackPreviousFlag = tc.to, tc.previous
+			defer func() { rollbackToFlag, rollbackPreviousFlag = prevTo, prevPrevious }()
+
+			target, err := resolveRollbackTarget(tc.records)
+			if tc.wantErr {
+				if err == nil {
+					t.Fatalf("resolveRollbackTarget() = %v, want error", target)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("resolveRollbackTarget() unexpected error: %v", err)
+			}
+			if target.BizVersion != tc.wantVersion {
+				t.Fatalf("resolveRollbackTarget() version = %q, want %q", target.BizVersion, tc.wantVersion)
+			}
+		})
+	}
+}
@@ -0,0 +1,217 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package deploy
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+
+	"serverless.alipay.com/sofa-serverless/arkctl/common/contextutil"
+)
+
+var (
+	watchFlag    bool
+	debounceFlag time.Duration
+)
+
+// runWatch rebuilds, re-parses and redeploys to target every time a source
+// file under buildFlag changes, giving the "inner loop" experience odo/devfile
+// popularized for Kubernetes development. It only returns once the watcher
+// itself fails or the process is interrupted.
+func runWatch(base *contextutil.Context) {
+	logger := contextutil.GetLogger(base)
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		logger.WithError(err).Error("start watch failed!")
+		return
+	}
+	defer watcher.Close()
+
+	if err := addWatchDirs(watcher, buildFlag); err != nil {
+		logger.WithError(err).Error("start watch failed!")
+		return
+	}
+
+	logger.WithField("dir", buildFlag).WithField("debounce", debounceFlag).Info("watching for changes.")
+
+	var (
+		mu     sync.Mutex
+		cancel context.CancelFunc
+		timer  *time.Timer
+	)
+
+	trigger := func() {
+		mu.Lock()
+		defer mu.Unlock()
+
+		if cancel != nil {
+			cancel()
+		}
+		goCtx, c := context.WithCancel(context.Background())
+		cancel = c
+
+		go runWatchCycle(contextutil.NewContext(goCtx), base)
+	}
+
+	for {
+		select {
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return
+			}
+
+			if event.Op&(fsnotify.Create|fsnotify.Write|fsnotify.Remove|fsnotify.Rename) == 0 {
+				continue
+			}
+			if fi, err := os.Stat(event.Name); err == nil && fi.IsDir() {
+				_ = watcher.Add(event.Name)
+				continue
+			}
+			if !isWatchedSource(buildFlag, event.Name) {
+				continue
+			}
+
+			if timer != nil {
+				timer.Stop()
+			}
+			timer = time.AfterFunc(debounceFlag, trigger)
+
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return
+			}
+			logger.WithError(err).Warn("watch error.")
+		}
+	}
+}
+
+// addWatchDirs registers watcher on root and every directory beneath it;
+// fsnotify only watches the directories it's explicitly told about.
+func addWatchDirs(watcher *fsnotify.Watcher, root string) error {
+	return filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			return watcher.Add(path)
+		}
+		return nil
+	})
+}
+
+// isWatchedSource reports whether path (relative to root) is one of the
+// inputs a rebuild cares about: *.java, pom.xml, *.gradle[.kts], or anything
+// under src/main/resources.
+func isWatchedSource(root, path string) bool {
+	rel, err := filepath.Rel(root, path)
+	if err != nil {
+		rel = path
+	}
+	rel = filepath.ToSlash(rel)
+
+	base := filepath.Base(rel)
+	switch {
+	case strings.HasSuffix(base, ".java"):
+		return true
+	case base == "pom.xml":
+		return true
+	case strings.HasSuffix(base, ".gradle") || strings.HasSuffix(base, ".gradle.kts"):
+		return true
+	case strings.Contains(rel, "src/main/resources/"):
+		return true
+	default:
+		return false
+	}
+}
+
+// runWatchCycle runs one build+deploy cycle in response to a file change,
+// printing structured, editor-parseable status lines as it goes. It
+// abandons the cycle without printing FAILED if ctx is cancelled by a
+// newer change arriving mid-build.
+func runWatchCycle(ctx *contextutil.Context, base *contextutil.Context) {
+	ctx.Put(ctxKeyArkService, base.Value(ctxKeyArkService))
+	ctx.Put(ctxKeyArkContainerRuntimeInfo, base.Value(ctxKeyArkContainerRuntimeInfo))
+
+	logger := contextutil.GetLogger(ctx)
+
+	fmt.Println("BUILDING")
+	if !execBuild(ctx) {
+		if ctx.Err() != nil {
+			return
+		}
+		fmt.Println("FAILED: build failed")
+		return
+	}
+
+	if !execParseBizModel(ctx) {
+		if ctx.Err() != nil {
+			return
+		}
+		fmt.Println("FAILED: parse biz model failed")
+		return
+	}
+
+	if ctx.Err() != nil {
+		return
+	}
+
+	fmt.Println("INSTALLING")
+	if !execUnInstall(ctx) {
+		if ctx.Err() != nil {
+			return
+		}
+		fmt.Println("FAILED: uninstall failed")
+		return
+	}
+	if !execInstall(ctx) {
+		if ctx.Err() != nil {
+			return
+		}
+		fmt.Println("FAILED: install failed")
+		return
+	}
+	if !execHealthCheck(ctx) {
+		if ctx.Err() != nil {
+			return
+		}
+		fmt.Println("FAILED: health check failed")
+		return
+	}
+
+	fmt.Println("READY")
+	logger.Info("watch: redeploy complete.")
+}
+
+func init() {
+	DeployCommand.Flags().BoolVar(&watchFlag, "watch", false, `
+Re-run the build+deploy cycle whenever a source file under --build changes.
+Only valid together with a local build (i.e. without --bundle) and without --selector.
+`)
+
+	DeployCommand.Flags().DurationVar(&debounceFlag, "debounce", 2*time.Second, `
+How long to wait for a burst of file changes to settle before triggering a rebuild, when --watch is set.
+`)
+}
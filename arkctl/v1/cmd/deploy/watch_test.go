@@ -0,0 +1,51 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package deploy
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestIsWatchedSource(t *testing.T) {
+	root := filepath.FromSlash("/project")
+
+	cases := []struct {
+		name string
+		path string
+		want bool
+	}{
+		{name: "java source", path: filepath.Join(root, "src/main/java/com/example/Foo.java"), want: true},
+		{name: "pom.xml at root", path: filepath.Join(root, "pom.xml"), want: true},
+		{name: "nested pom.xml", path: filepath.Join(root, "submodule/pom.xml"), want: true},
+		{name: "gradle groovy build file", path: filepath.Join(root, "build.gradle"), want: true},
+		{name: "gradle kotlin build file", path: filepath.Join(root, "build.gradle.kts"), want: true},
+		{name: "resource under src/main/resources", path: filepath.Join(root, "src/main/resources/application.yml"), want: true},
+		{name: "compiled class file", path: filepath.Join(root, "target/classes/Foo.class"), want: false},
+		{name: "unrelated readme", path: filepath.Join(root, "README.md"), want: false},
+		{name: "test resource outside src/main", path: filepath.Join(root, "src/test/resources/application.yml"), want: false},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := isWatchedSource(root, tc.path); got != tc.want {
+				t.Fatalf("isWatchedSource(%q, %q) = %v, want %v", root, tc.path, got, tc.want)
+			}
+		})
+	}
+}